@@ -0,0 +1,125 @@
+package jaeger
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"time"
+
+	"github.com/apache/thrift/lib/go/thrift"
+	v1 "github.com/jaegertracing/jaeger-idl/model/v1"
+	api_v2 "github.com/jaegertracing/jaeger-idl/proto-gen/api_v2"
+	"github.com/jaegertracing/jaeger-idl/thrift-gen/agent"
+)
+
+// maxAgentPacketSize is the largest UDP datagram the agent listener will
+// read, matching the classic jaeger-agent thrift/udp receiver.
+const maxAgentPacketSize = 65 * 1024
+
+// malformedPacketLogInterval limits how often a dropped-packet warning (a
+// malformed packet or a failed PostSpans) is logged, so a misbehaving client
+// can't flood the log.
+const malformedPacketLogInterval = 5 * time.Second
+
+// DefaultAgentUDPAddr is the well-known jaeger-agent compact-thrift port.
+const DefaultAgentUDPAddr = ":6831"
+
+// ListenAgentUDP opens a UDP socket on addr (defaulting to
+// DefaultAgentUDPAddr when empty) and serves the classic jaeger-agent
+// thrift-compact protocol: each datagram is a oneway Agent.emitBatch
+// message envelope wrapping a jaeger.Batch, decoded with
+// thrift.NewTCompactProtocol over a TMemoryBuffer and forwarded through
+// client.PostSpans via the same convertSpan/convertProcess path HandleTraces
+// uses. It blocks serving packets until the socket is closed or an
+// unrecoverable read error occurs.
+func ListenAgentUDP(addr string, client api_v2.CollectorServiceClient) error {
+	if addr == "" {
+		addr = DefaultAgentUDPAddr
+	}
+
+	udpAddr, err := net.ResolveUDPAddr("udp", addr)
+	if err != nil {
+		return err
+	}
+	conn, err := net.ListenUDP("udp", udpAddr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	log.Println("jaeger agent: listening for compact-thrift spans on", conn.LocalAddr())
+
+	buf := make([]byte, maxAgentPacketSize)
+	var lastMalformedLog time.Time
+	for {
+		n, _, err := conn.ReadFromUDP(buf)
+		if err != nil {
+			if ne, ok := err.(net.Error); ok && ne.Timeout() {
+				continue
+			}
+			return err
+		}
+
+		if err := handleAgentPacket(buf[:n], client); err != nil {
+			if time.Since(lastMalformedLog) >= malformedPacketLogInterval {
+				log.Println("jaeger agent: dropping packet:", err)
+				lastMalformedLog = time.Now()
+			}
+		}
+	}
+}
+
+// handleAgentPacket decodes a single compact-thrift datagram as an
+// Agent.emitBatch oneway message (message envelope + emitBatch_args, not a
+// bare Batch struct, which is what real Jaeger SDKs and the OTel Jaeger
+// exporter's agent mode actually send) and forwards the enclosed batch to
+// client.PostSpans.
+func handleAgentPacket(data []byte, client api_v2.CollectorServiceClient) error {
+	transport := thrift.NewTMemoryBuffer()
+	if _, err := transport.Write(data); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	proto := thrift.NewTCompactProtocol(transport)
+
+	name, _, _, err := proto.ReadMessageBegin(ctx)
+	if err != nil {
+		return err
+	}
+	if name != "emitBatch" {
+		return fmt.Errorf("jaeger agent: unexpected message %q", name)
+	}
+
+	args := agent.NewAgentEmitBatchArgs()
+	if err := args.Read(ctx, proto); err != nil {
+		return err
+	}
+	if err := proto.ReadMessageEnd(ctx); err != nil {
+		return err
+	}
+
+	batch := args.Batch
+	if batch == nil {
+		return fmt.Errorf("jaeger agent: emitBatch message carried no batch")
+	}
+
+	spans := make([]*v1.Span, 0, len(batch.Spans))
+	proc := convertProcess(batch.Process)
+	for _, s := range batch.Spans {
+		span := convertSpan(s)
+		span.Process = proc
+		spans = append(spans, span)
+	}
+
+	req := &api_v2.PostSpansRequest{
+		Batch: v1.Batch{
+			Spans:   spans,
+			Process: proc,
+		},
+	}
+
+	_, err = client.PostSpans(ctx, req)
+	return err
+}
@@ -0,0 +1,75 @@
+package jaeger
+
+import (
+	"context"
+	"testing"
+
+	"github.com/apache/thrift/lib/go/thrift"
+	api_v2 "github.com/jaegertracing/jaeger-idl/proto-gen/api_v2"
+	"github.com/jaegertracing/jaeger-idl/thrift-gen/agent"
+	jaegerthrift "github.com/jaegertracing/jaeger-idl/thrift-gen/jaeger"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+type fakeCollectorClient struct {
+	gotReq *api_v2.PostSpansRequest
+}
+
+func (f *fakeCollectorClient) PostSpans(ctx context.Context, in *api_v2.PostSpansRequest, opts ...grpc.CallOption) (*api_v2.PostSpansResponse, error) {
+	f.gotReq = in
+	return &api_v2.PostSpansResponse{}, nil
+}
+
+// encodeEmitBatch serializes batch as a compact-thrift Agent.emitBatch
+// oneway message, mirroring what a real jaeger-agent UDP client sends.
+func encodeEmitBatch(t *testing.T, batch *jaegerthrift.Batch) []byte {
+	t.Helper()
+	transport := thrift.NewTMemoryBuffer()
+	proto := thrift.NewTCompactProtocol(transport)
+	ctx := context.Background()
+
+	require.NoError(t, proto.WriteMessageBegin(ctx, "emitBatch", thrift.ONEWAY, 0))
+	args := agent.NewAgentEmitBatchArgs()
+	args.Batch = batch
+	require.NoError(t, args.Write(ctx, proto))
+	require.NoError(t, proto.WriteMessageEnd(ctx))
+	require.NoError(t, proto.Flush(ctx))
+
+	return transport.Bytes()
+}
+
+func TestHandleAgentPacket_DecodesEmitBatchMessage(t *testing.T) {
+	batch := jaegerthrift.NewBatch()
+	batch.Process = &jaegerthrift.Process{ServiceName: "svc"}
+	batch.Spans = []*jaegerthrift.Span{
+		{TraceIdLow: 1, SpanId: 2, OperationName: "op"},
+	}
+
+	data := encodeEmitBatch(t, batch)
+
+	client := &fakeCollectorClient{}
+	require.NoError(t, handleAgentPacket(data, client))
+
+	require.NotNil(t, client.gotReq)
+	require.Len(t, client.gotReq.Batch.Spans, 1)
+	require.Equal(t, "op", client.gotReq.Batch.Spans[0].OperationName)
+	require.Equal(t, "svc", client.gotReq.Batch.Process.ServiceName)
+}
+
+func TestHandleAgentPacket_RejectsBareBatchStruct(t *testing.T) {
+	// A bare Batch struct (the old, incorrect assumption) is not a valid
+	// emitBatch message envelope and must be reported as malformed rather
+	// than silently misparsed.
+	batch := jaegerthrift.NewBatch()
+	batch.Process = &jaegerthrift.Process{ServiceName: "svc"}
+
+	transport := thrift.NewTMemoryBuffer()
+	proto := thrift.NewTCompactProtocol(transport)
+	ctx := context.Background()
+	require.NoError(t, batch.Write(ctx, proto))
+	require.NoError(t, proto.Flush(ctx))
+
+	client := &fakeCollectorClient{}
+	require.Error(t, handleAgentPacket(transport.Bytes(), client))
+}
@@ -9,8 +9,9 @@ import (
 
 	"github.com/apache/thrift/lib/go/thrift"
 	v1 "github.com/jaegertracing/jaeger-idl/model/v1"
-	api_v2 "github.com/jaegertracing/jaeger-idl/proto-gen/api_v2"
 	jaegerthrift "github.com/jaegertracing/jaeger-idl/thrift-gen/jaeger"
+
+	"github.com/tra4less/pgweb/pkg/propagation"
 )
 
 // convertTag converts a Thrift Tag to a v1.KeyValue.
@@ -147,14 +148,22 @@ func convertProcess(p *jaegerthrift.Process) *v1.Process {
 }
 
 // HandleTraces returns an http.HandlerFunc that receives Thrift-encoded Jaeger
-// spans over HTTP and forwards them to a Jaeger gRPC collector.
-func HandleTraces(client api_v2.CollectorServiceClient) http.HandlerFunc {
+// spans over HTTP and hands them to fwd for asynchronous forwarding to a
+// Jaeger gRPC collector; PostSpans is never called on the request path
+// itself. When fwd's queue is full, the handler responds with 429 and a
+// Retry-After header instead of blocking or failing hard.
+func HandleTraces(fwd *SpanForwarder) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodPost {
 			w.WriteHeader(http.StatusMethodNotAllowed)
 			return
 		}
 
+		if sc, ok := propagation.Extract(r.Header); ok {
+			log.Printf("jaeger ingest: incoming traceparent trace=%016x%016x span=%016x sampled=%v",
+				sc.TraceID.High, sc.TraceID.Low, uint64(sc.SpanID), sc.Sampled())
+		}
+
 		data, err := io.ReadAll(r.Body)
 		if err != nil {
 			log.Println("read body error:", err)
@@ -199,14 +208,9 @@ func HandleTraces(client api_v2.CollectorServiceClient) http.HandlerFunc {
 			Process: proc,
 		}
 
-		req := &api_v2.PostSpansRequest{
-			Batch: grpcBatch,
-		}
-
-		_, err = client.PostSpans(context.Background(), req)
-		if err != nil {
-			log.Println("gRPC PostSpans error:", err)
-			w.WriteHeader(http.StatusInternalServerError)
+		if err := fwd.Enqueue(grpcBatch); err != nil {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
 			return
 		}
 
@@ -0,0 +1,291 @@
+package jaeger
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	v1 "github.com/jaegertracing/jaeger-idl/model/v1"
+	api_v2 "github.com/jaegertracing/jaeger-idl/proto-gen/api_v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrQueueFull is returned by SpanForwarder.Enqueue when the forwarder's
+// queue is at capacity; callers should surface this as HTTP 429.
+var ErrQueueFull = errors.New("jaeger: span forwarder queue is full")
+
+// ForwarderConfig configures a SpanForwarder.
+type ForwarderConfig struct {
+	// QueueSize bounds the number of pending batches. Defaults to 1024.
+	QueueSize int
+	// Workers is the number of goroutines draining the queue. Defaults to 4.
+	Workers int
+	// MaxBatchSpans coalesces consecutive same-service batches until this
+	// many spans have accumulated. Defaults to 1000.
+	MaxBatchSpans int
+	// MaxBatchWait coalesces consecutive same-service batches for at most
+	// this long before flushing regardless of size. Defaults to 1s.
+	MaxBatchWait time.Duration
+	// InitialBackoff and MaxBackoff bound the exponential retry delay for
+	// transient PostSpans errors. Default to 100ms and 10s.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	// MaxRetries caps retry attempts per batch before it is dropped.
+	// Defaults to 5.
+	MaxRetries int
+}
+
+func (c ForwarderConfig) withDefaults() ForwarderConfig {
+	if c.QueueSize <= 0 {
+		c.QueueSize = 1024
+	}
+	if c.Workers <= 0 {
+		c.Workers = 4
+	}
+	if c.MaxBatchSpans <= 0 {
+		c.MaxBatchSpans = 1000
+	}
+	if c.MaxBatchWait <= 0 {
+		c.MaxBatchWait = time.Second
+	}
+	if c.InitialBackoff <= 0 {
+		c.InitialBackoff = 100 * time.Millisecond
+	}
+	if c.MaxBackoff <= 0 {
+		c.MaxBackoff = 10 * time.Second
+	}
+	if c.MaxRetries <= 0 {
+		c.MaxRetries = 5
+	}
+	return c
+}
+
+var (
+	forwarderSpansAccepted = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "jaeger_bridge_forwarder_spans_accepted_total",
+		Help: "Spans accepted onto the SpanForwarder queue.",
+	})
+	forwarderSpansDropped = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "jaeger_bridge_forwarder_spans_dropped_total",
+		Help: "Spans dropped because the SpanForwarder queue was full or retries were exhausted.",
+	})
+	forwarderSpansRetried = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "jaeger_bridge_forwarder_spans_retried_total",
+		Help: "Spans the SpanForwarder re-sent to PostSpans after a transient error.",
+	})
+)
+
+func init() {
+	for _, c := range []prometheus.Collector{forwarderSpansAccepted, forwarderSpansDropped, forwarderSpansRetried} {
+		if err := prometheus.Register(c); err != nil {
+			var are prometheus.AlreadyRegisteredError
+			if !errors.As(err, &are) {
+				log.Println("jaeger forwarder: metric registration failed:", err)
+			}
+		}
+	}
+}
+
+// SpanForwarder decouples span ingestion from the gRPC PostSpans call: it
+// queues incoming batches, coalesces same-service batches up to a
+// size/time threshold, and retries transient gRPC errors with exponential
+// backoff, so a slow or momentarily unavailable collector applies
+// backpressure (via Enqueue returning ErrQueueFull) instead of failing the
+// ingesting HTTP request outright.
+type SpanForwarder struct {
+	client api_v2.CollectorServiceClient
+	cfg    ForwarderConfig
+
+	queue chan v1.Batch
+
+	wg       sync.WaitGroup
+	stopOnce sync.Once
+	stop     chan struct{}
+}
+
+// NewSpanForwarder returns a SpanForwarder that forwards to client. Call
+// Start to begin processing and Close to drain and stop it.
+func NewSpanForwarder(client api_v2.CollectorServiceClient, cfg ForwarderConfig) *SpanForwarder {
+	cfg = cfg.withDefaults()
+	return &SpanForwarder{
+		client: client,
+		cfg:    cfg,
+		queue:  make(chan v1.Batch, cfg.QueueSize),
+		stop:   make(chan struct{}),
+	}
+}
+
+// Start launches the forwarder's worker goroutines. It must be called
+// before Enqueue.
+func (f *SpanForwarder) Start() {
+	for i := 0; i < f.cfg.Workers; i++ {
+		f.wg.Add(1)
+		go f.worker()
+	}
+}
+
+// Enqueue accepts a batch for asynchronous forwarding. It returns
+// ErrQueueFull without blocking when the queue is at capacity.
+func (f *SpanForwarder) Enqueue(batch v1.Batch) error {
+	select {
+	case f.queue <- batch:
+		forwarderSpansAccepted.Add(float64(len(batch.Spans)))
+		return nil
+	default:
+		forwarderSpansDropped.Add(float64(len(batch.Spans)))
+		return ErrQueueFull
+	}
+}
+
+// Close stops accepting new work implicitly (callers must stop calling
+// Enqueue) and waits for queued batches to drain, or ctx to be done,
+// whichever comes first.
+func (f *SpanForwarder) Close(ctx context.Context) error {
+	f.stopOnce.Do(func() { close(f.stop) })
+
+	done := make(chan struct{})
+	go func() {
+		f.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// worker drains the queue, coalescing consecutive batches that share a
+// service name until MaxBatchSpans or MaxBatchWait is reached, then flushes.
+func (f *SpanForwarder) worker() {
+	defer f.wg.Done()
+
+	var pending *v1.Batch
+	var pendingSince time.Time
+	timer := time.NewTimer(f.cfg.MaxBatchWait)
+	defer timer.Stop()
+
+	flush := func() {
+		if pending == nil || len(pending.Spans) == 0 {
+			return
+		}
+		f.postWithRetry(*pending)
+		pending = nil
+	}
+
+	for {
+		select {
+		case <-f.stop:
+			// Drain whatever is already queued before exiting.
+			for {
+				select {
+				case batch := <-f.queue:
+					if switchesService(pending, batch) {
+						flush()
+					}
+					if pending == nil {
+						pendingSince = time.Now()
+					}
+					pending = coalesce(pending, batch)
+					if len(pending.Spans) >= f.cfg.MaxBatchSpans {
+						flush()
+					}
+				default:
+					flush()
+					return
+				}
+			}
+
+		case batch, ok := <-f.queue:
+			if !ok {
+				flush()
+				return
+			}
+			if switchesService(pending, batch) {
+				flush()
+			}
+			if pending == nil {
+				pendingSince = time.Now()
+			}
+			pending = coalesce(pending, batch)
+			if len(pending.Spans) >= f.cfg.MaxBatchSpans || time.Since(pendingSince) >= f.cfg.MaxBatchWait {
+				flush()
+				timer.Reset(f.cfg.MaxBatchWait)
+			}
+
+		case <-timer.C:
+			flush()
+			timer.Reset(f.cfg.MaxBatchWait)
+		}
+	}
+}
+
+// switchesService reports whether appending next to pending would mix
+// spans from two different services, meaning pending must be flushed
+// first so its spans are never silently dropped in favor of next's.
+func switchesService(pending *v1.Batch, next v1.Batch) bool {
+	return pending != nil && pending.Process.ServiceName != next.Process.ServiceName
+}
+
+// coalesce merges next into pending when they share a service name,
+// otherwise it starts a fresh pending batch from next. Callers must check
+// switchesService and flush the old pending batch first so its spans are
+// never silently dropped in favor of next's.
+func coalesce(pending *v1.Batch, next v1.Batch) *v1.Batch {
+	if pending == nil || pending.Process.ServiceName != next.Process.ServiceName {
+		b := next
+		return &b
+	}
+	pending.Spans = append(pending.Spans, next.Spans...)
+	return pending
+}
+
+// postWithRetry calls client.PostSpans, retrying transient errors
+// (Unavailable, DeadlineExceeded, ResourceExhausted) with exponential
+// backoff up to cfg.MaxRetries before dropping the batch.
+func (f *SpanForwarder) postWithRetry(batch v1.Batch) {
+	backoff := f.cfg.InitialBackoff
+	for attempt := 0; ; attempt++ {
+		_, err := f.client.PostSpans(context.Background(), &api_v2.PostSpansRequest{Batch: batch})
+		if err == nil {
+			return
+		}
+		if !isTransient(err) || attempt >= f.cfg.MaxRetries {
+			log.Println("jaeger forwarder: dropping batch after", attempt+1, "attempts:", err)
+			forwarderSpansDropped.Add(float64(len(batch.Spans)))
+			return
+		}
+
+		forwarderSpansRetried.Add(float64(len(batch.Spans)))
+		select {
+		case <-time.After(backoff):
+		case <-f.stop:
+			return
+		}
+		backoff *= 2
+		if backoff > f.cfg.MaxBackoff {
+			backoff = f.cfg.MaxBackoff
+		}
+	}
+}
+
+// isTransient reports whether err is a gRPC status generally worth
+// retrying.
+func isTransient(err error) bool {
+	st, ok := status.FromError(err)
+	if !ok {
+		return false
+	}
+	switch st.Code() {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted:
+		return true
+	default:
+		return false
+	}
+}
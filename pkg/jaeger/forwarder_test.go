@@ -0,0 +1,50 @@
+package jaeger
+
+import (
+	"testing"
+
+	v1 "github.com/jaegertracing/jaeger-idl/model/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCoalesce_SameServiceMerges(t *testing.T) {
+	pending := &v1.Batch{
+		Process: &v1.Process{ServiceName: "svc"},
+		Spans:   []*v1.Span{{SpanID: 1}},
+	}
+	next := v1.Batch{
+		Process: &v1.Process{ServiceName: "svc"},
+		Spans:   []*v1.Span{{SpanID: 2}},
+	}
+
+	got := coalesce(pending, next)
+	require.Len(t, got.Spans, 2)
+	assert.Equal(t, v1.SpanID(1), got.Spans[0].SpanID)
+	assert.Equal(t, v1.SpanID(2), got.Spans[1].SpanID)
+}
+
+func TestCoalesce_DifferentServiceReplaces(t *testing.T) {
+	pending := &v1.Batch{
+		Process: &v1.Process{ServiceName: "a"},
+		Spans:   []*v1.Span{{SpanID: 1}},
+	}
+	next := v1.Batch{
+		Process: &v1.Process{ServiceName: "b"},
+		Spans:   []*v1.Span{{SpanID: 2}},
+	}
+
+	got := coalesce(pending, next)
+	require.Len(t, got.Spans, 1)
+	assert.Equal(t, v1.SpanID(2), got.Spans[0].SpanID)
+}
+
+func TestIsTransient(t *testing.T) {
+	assert.True(t, isTransient(status.Error(codes.Unavailable, "down")))
+	assert.True(t, isTransient(status.Error(codes.DeadlineExceeded, "slow")))
+	assert.True(t, isTransient(status.Error(codes.ResourceExhausted, "busy")))
+	assert.False(t, isTransient(status.Error(codes.InvalidArgument, "bad")))
+	assert.False(t, isTransient(assert.AnError))
+}
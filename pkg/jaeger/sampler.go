@@ -0,0 +1,159 @@
+package jaeger
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	jaegerthrift "github.com/jaegertracing/jaeger-idl/thrift-gen/jaeger"
+)
+
+// maxInt63 mirrors the sampling decision Jaeger clients make: a trace is
+// sampled when (TraceID.Low & 0x7fffffffffffffff) < p * maxInt63, so every
+// span of a trace shares the same probabilistic decision.
+const maxInt63 = int64(math.MaxInt64)
+
+// Sampler decides whether a span belonging to service should be kept,
+// given the low 64 bits of its trace ID (the same bits Jaeger clients use
+// to make deterministic per-trace decisions).
+type Sampler interface {
+	IsSampled(traceIDLow uint64) bool
+}
+
+// ProbabilisticSampler samples a fixed fraction p (in [0,1]) of traces,
+// deterministically by TraceID.Low so every span of a trace agrees.
+type ProbabilisticSampler struct {
+	boundary uint64
+}
+
+// NewProbabilisticSampler returns a Sampler that keeps traces whose
+// (TraceID.Low & 0x7fffffffffffffff) falls under p * MaxInt63.
+//
+// boundary is stored as a uint64 rather than int64: at p=1 it must hold
+// 2^63 (float64(math.MaxInt64) rounds up to exactly 2^63), which overflows
+// int64 and wraps to a negative number, making IsSampled always false.
+func NewProbabilisticSampler(p float64) *ProbabilisticSampler {
+	if p < 0 {
+		p = 0
+	}
+	if p > 1 {
+		p = 1
+	}
+	return &ProbabilisticSampler{boundary: uint64(p * float64(maxInt63))}
+}
+
+// IsSampled implements Sampler.
+func (s *ProbabilisticSampler) IsSampled(traceIDLow uint64) bool {
+	return traceIDLow&0x7fffffffffffffff < s.boundary
+}
+
+// RateLimitingSampler samples at most maxTracesPerSecond new traces per
+// second, using a simple token bucket with a capacity of one second's
+// worth of traces.
+type RateLimitingSampler struct {
+	mu         sync.Mutex
+	rate       float64
+	balance    float64
+	lastTick   time.Time
+	maxBalance float64
+}
+
+// NewRateLimitingSampler returns a Sampler that admits up to
+// maxTracesPerSecond traces/sec, bursting up to one second's worth.
+func NewRateLimitingSampler(maxTracesPerSecond float64) *RateLimitingSampler {
+	if maxTracesPerSecond < 0 {
+		maxTracesPerSecond = 0
+	}
+	return &RateLimitingSampler{
+		rate:       maxTracesPerSecond,
+		balance:    maxTracesPerSecond,
+		maxBalance: maxTracesPerSecond,
+		lastTick:   time.Now(),
+	}
+}
+
+// IsSampled implements Sampler. Each call that represents a new trace
+// consumes one token; traceIDLow is accepted to satisfy the Sampler
+// interface but the decision does not depend on it.
+func (s *RateLimitingSampler) IsSampled(_ uint64) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	elapsed := now.Sub(s.lastTick).Seconds()
+	s.lastTick = now
+	s.balance += elapsed * s.rate
+	if s.balance > s.maxBalance {
+		s.balance = s.maxBalance
+	}
+	if s.balance < 1 {
+		return false
+	}
+	s.balance--
+	return true
+}
+
+// GuaranteedThroughputSampler wraps a ProbabilisticSampler with a
+// RateLimitingSampler floor, so at least lowerBound traces/sec get through
+// even when the probabilistic sampler would otherwise drop everything.
+type GuaranteedThroughputSampler struct {
+	probabilistic *ProbabilisticSampler
+	lowerBound    *RateLimitingSampler
+}
+
+// NewGuaranteedThroughputSampler returns a Sampler combining a
+// probabilistic rate p with a guaranteed lowerBound traces/sec floor.
+func NewGuaranteedThroughputSampler(p float64, lowerBound float64) *GuaranteedThroughputSampler {
+	return &GuaranteedThroughputSampler{
+		probabilistic: NewProbabilisticSampler(p),
+		lowerBound:    NewRateLimitingSampler(lowerBound),
+	}
+}
+
+// IsSampled implements Sampler.
+func (s *GuaranteedThroughputSampler) IsSampled(traceIDLow uint64) bool {
+	if s.probabilistic.IsSampled(traceIDLow) {
+		return true
+	}
+	return s.lowerBound.IsSampled(traceIDLow)
+}
+
+// samplerFromTag builds a Sampler from a batch's "sampler.type"/
+// "sampler.param" process tags, if present, mirroring the remote-sampling
+// strategy a Jaeger client already applied to itself.
+func samplerFromTag(proc *jaegerthrift.Process) (Sampler, bool) {
+	if proc == nil {
+		return nil, false
+	}
+	var typ string
+	var param float64
+	var haveType, haveParam bool
+	for _, t := range proc.Tags {
+		switch t.Key {
+		case "sampler.type":
+			if t.VStr != nil {
+				typ = *t.VStr
+				haveType = true
+			}
+		case "sampler.param":
+			if t.VDouble != nil {
+				param = *t.VDouble
+				haveParam = true
+			} else if t.VLong != nil {
+				param = float64(*t.VLong)
+				haveParam = true
+			}
+		}
+	}
+	if !haveType || !haveParam {
+		return nil, false
+	}
+	switch typ {
+	case "probabilistic":
+		return NewProbabilisticSampler(param), true
+	case "ratelimiting":
+		return NewRateLimitingSampler(param), true
+	default:
+		return nil, false
+	}
+}
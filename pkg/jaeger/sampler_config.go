@@ -0,0 +1,121 @@
+package jaeger
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// StrategyConfig is one entry of the remote-sampling strategies file format
+// used by Jaeger clients: a sampler type ("probabilistic", "ratelimiting"
+// or "guaranteed") with its parameter(s).
+type StrategyConfig struct {
+	Type               string  `json:"type"`
+	Param              float64 `json:"param"`
+	LowerBound         float64 `json:"lowerBound,omitempty"`
+	MaxTracesPerSecond float64 `json:"maxTracesPerSecond,omitempty"`
+}
+
+// SamplerConfig is the per-service sampling configuration loaded from a
+// JSON strategies file: a default strategy plus optional overrides keyed
+// by service name.
+type SamplerConfig struct {
+	Default           StrategyConfig            `json:"default_strategy"`
+	ServiceStrategies map[string]StrategyConfig `json:"service_strategies"`
+}
+
+// buildSampler turns a StrategyConfig into a concrete Sampler. A completely
+// zero-value StrategyConfig (no default_strategy configured at all) is
+// treated as "sample everything" rather than as an explicit
+// probabilistic(0) — the latter would silently drop all spans for anyone
+// who didn't realize a default_strategy is required, which is the last
+// thing an operator wants from a tracing pipeline.
+func buildSampler(cfg StrategyConfig) Sampler {
+	if cfg == (StrategyConfig{}) {
+		return NewProbabilisticSampler(1)
+	}
+	switch cfg.Type {
+	case "ratelimiting":
+		return NewRateLimitingSampler(cfg.Param)
+	case "guaranteed":
+		return NewGuaranteedThroughputSampler(cfg.Param, cfg.LowerBound)
+	default: // "probabilistic" and unset
+		return NewProbabilisticSampler(cfg.Param)
+	}
+}
+
+// LoadSamplerConfig reads and parses a sampling strategies JSON file from
+// path, in the same shape Jaeger clients fetch from a remote sampling
+// endpoint.
+func LoadSamplerConfig(path string) (SamplerConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return SamplerConfig{}, err
+	}
+	var cfg SamplerConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return SamplerConfig{}, err
+	}
+	return cfg, nil
+}
+
+// SamplerConfigSource holds the currently active SamplerConfig and
+// (optionally) keeps it fresh by reloading a JSON file on an interval. It
+// is safe for concurrent use.
+type SamplerConfigSource struct {
+	mu         sync.RWMutex
+	cfg        SamplerConfig
+	generation uint64
+}
+
+// NewSamplerConfigSource returns a SamplerConfigSource seeded with cfg.
+func NewSamplerConfigSource(cfg SamplerConfig) *SamplerConfigSource {
+	return &SamplerConfigSource{cfg: cfg}
+}
+
+// Get returns the currently active SamplerConfig.
+func (s *SamplerConfigSource) Get() SamplerConfig {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.cfg
+}
+
+// Generation returns a counter that increments every time the config is
+// replaced via set, so callers can cheaply detect a reload and invalidate
+// anything they cached from a prior SamplerConfig.
+func (s *SamplerConfigSource) Generation() uint64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.generation
+}
+
+// set replaces the currently active SamplerConfig and bumps Generation.
+func (s *SamplerConfigSource) set(cfg SamplerConfig) {
+	s.mu.Lock()
+	s.cfg = cfg
+	s.generation++
+	s.mu.Unlock()
+}
+
+// WatchFile periodically reloads path (every interval) into s, logging and
+// keeping the previous config on any read/parse error. It runs until stop
+// is closed.
+func (s *SamplerConfigSource) WatchFile(path string, interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			cfg, err := LoadSamplerConfig(path)
+			if err != nil {
+				log.Println("jaeger sampler: failed to reload", path, ":", err)
+				continue
+			}
+			s.set(cfg)
+		}
+	}
+}
@@ -0,0 +1,20 @@
+package jaeger
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildSampler_ZeroValueStrategySamplesEverything(t *testing.T) {
+	s := buildSampler(StrategyConfig{})
+	assert.True(t, s.IsSampled(1))
+	assert.True(t, s.IsSampled(math.MaxUint64))
+}
+
+func TestBuildSampler_ExplicitProbabilisticZeroDropsEverything(t *testing.T) {
+	s := buildSampler(StrategyConfig{Type: "probabilistic", Param: 0})
+	assert.False(t, s.IsSampled(1))
+	assert.False(t, s.IsSampled(math.MaxUint64))
+}
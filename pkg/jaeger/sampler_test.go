@@ -0,0 +1,41 @@
+package jaeger
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProbabilisticSampler_Deterministic(t *testing.T) {
+	s := NewProbabilisticSampler(0.5)
+	var low uint64 = 123456789
+	first := s.IsSampled(low)
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, first, s.IsSampled(low))
+	}
+}
+
+func TestProbabilisticSampler_ZeroNeverSamples(t *testing.T) {
+	s := NewProbabilisticSampler(0)
+	assert.False(t, s.IsSampled(1))
+	assert.False(t, s.IsSampled(math.MaxUint64))
+}
+
+func TestProbabilisticSampler_OneAlwaysSamples(t *testing.T) {
+	s := NewProbabilisticSampler(1)
+	assert.True(t, s.IsSampled(1))
+	assert.True(t, s.IsSampled(math.MaxUint64))
+}
+
+func TestRateLimitingSampler_BurstThenBlocks(t *testing.T) {
+	s := NewRateLimitingSampler(2)
+	assert.True(t, s.IsSampled(0))
+	assert.True(t, s.IsSampled(0))
+	assert.False(t, s.IsSampled(0))
+}
+
+func TestGuaranteedThroughputSampler_FallsBackToFloor(t *testing.T) {
+	s := NewGuaranteedThroughputSampler(0, 1)
+	assert.True(t, s.IsSampled(0))
+}
@@ -0,0 +1,149 @@
+package jaeger
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+
+	"github.com/apache/thrift/lib/go/thrift"
+	jaegerthrift "github.com/jaegertracing/jaeger-idl/thrift-gen/jaeger"
+)
+
+// SamplingHandler wraps an http.Handler (typically HandleTraces) with a
+// sampling stage: it decodes the incoming Thrift batch, drops spans per
+// the configured per-service sampler, re-encodes the survivors, and only
+// then calls next. This keeps HandleTraces itself unaware of sampling.
+type SamplingHandler struct {
+	next   http.Handler
+	source *SamplerConfigSource
+
+	mu          sync.Mutex
+	samplers    map[string]Sampler
+	cachedAtGen uint64
+}
+
+// NewSamplingHandler returns an http.Handler that samples Thrift-encoded
+// Jaeger batches before delegating to next.
+func NewSamplingHandler(next http.Handler, cfg SamplerConfig) *SamplingHandler {
+	return &SamplingHandler{
+		next:     next,
+		source:   NewSamplerConfigSource(cfg),
+		samplers: make(map[string]Sampler),
+	}
+}
+
+// Source returns the SamplerConfigSource backing h, so callers can start
+// SamplerConfigSource.WatchFile against it to pick up live config changes.
+func (h *SamplingHandler) Source() *SamplerConfigSource {
+	return h.source
+}
+
+// samplerForService returns the (cached) Sampler configured for service,
+// building and caching it from the current SamplerConfig on first use.
+// The cache is cleared whenever source's Generation moves past the
+// generation it was built from, so a WatchFile reload takes effect on the
+// next request instead of being stuck with whatever was first observed.
+func (h *SamplingHandler) samplerForService(service string) Sampler {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if gen := h.source.Generation(); gen != h.cachedAtGen {
+		h.samplers = make(map[string]Sampler)
+		h.cachedAtGen = gen
+	}
+
+	if s, ok := h.samplers[service]; ok {
+		return s
+	}
+
+	cfg := h.source.Get()
+	strategy := cfg.Default
+	if svc, ok := cfg.ServiceStrategies[service]; ok {
+		strategy = svc
+	}
+	s := buildSampler(strategy)
+	h.samplers[service] = s
+	return s
+}
+
+func (h *SamplingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.next.ServeHTTP(w, r)
+		return
+	}
+
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		log.Println("jaeger sampler: read body error:", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if err := r.Body.Close(); err != nil {
+		log.Println("jaeger sampler: body close error:", err)
+	}
+
+	transport := thrift.NewTMemoryBuffer()
+	if _, err := transport.Write(data); err != nil {
+		log.Println("jaeger sampler: thrift buffer write failed:", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	proto := thrift.NewTBinaryProtocolTransport(transport)
+	batch := jaegerthrift.NewBatch()
+	if err := batch.Read(context.Background(), proto); err != nil {
+		log.Println("jaeger sampler: thrift decode failed:", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	h.filterBatch(batch)
+
+	outBuf := thrift.NewTMemoryBuffer()
+	outProto := thrift.NewTBinaryProtocolTransport(outBuf)
+	if err := batch.Write(context.Background(), outProto); err != nil {
+		log.Println("jaeger sampler: thrift re-encode failed:", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	r.Body = io.NopCloser(bytes.NewReader(outBuf.Bytes()))
+	r.ContentLength = int64(outBuf.Len())
+	h.next.ServeHTTP(w, r)
+}
+
+// filterBatch drops spans from batch according to the effective sampler
+// for its process: an explicit sampler.type/sampler.param process tag
+// takes precedence over the per-service config.
+func (h *SamplingHandler) filterBatch(batch *jaegerthrift.Batch) {
+	var sampler Sampler
+	if s, ok := samplerFromTag(batch.Process); ok {
+		sampler = s
+	} else {
+		service := ""
+		if batch.Process != nil {
+			service = batch.Process.ServiceName
+		}
+		sampler = h.samplerForService(service)
+	}
+
+	// Decide once per trace, so every span of a trace in this batch shares
+	// one decision and a rate-limiting sampler spends one token per trace.
+	type traceKey struct{ high, low int64 }
+	decisions := make(map[traceKey]bool)
+	kept := batch.Spans[:0]
+	for _, s := range batch.Spans {
+		key := traceKey{s.TraceIdHigh, s.TraceIdLow}
+		sampled, seen := decisions[key]
+		if !seen {
+			sampled = sampler.IsSampled(uint64(s.TraceIdLow))
+			decisions[key] = sampled
+		}
+		if sampled {
+			kept = append(kept, s)
+		}
+	}
+	batch.Spans = kept
+}
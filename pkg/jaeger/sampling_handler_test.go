@@ -0,0 +1,24 @@
+package jaeger
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSamplingHandler_SamplerForService_PicksUpReload(t *testing.T) {
+	h := NewSamplingHandler(http.NotFoundHandler(), SamplerConfig{
+		Default: StrategyConfig{Type: "probabilistic", Param: 0},
+	})
+
+	s1 := h.samplerForService("svc")
+	assert.False(t, s1.IsSampled(1))
+
+	h.source.set(SamplerConfig{
+		Default: StrategyConfig{Type: "probabilistic", Param: 1},
+	})
+
+	s2 := h.samplerForService("svc")
+	assert.True(t, s2.IsSampled(1))
+}
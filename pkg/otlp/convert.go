@@ -0,0 +1,196 @@
+// Package otlp receives OpenTelemetry OTLP trace data (over gRPC or HTTP)
+// and converts it into the Jaeger v1 model so it can be forwarded through
+// the same api_v2.CollectorServiceClient the jaeger package uses.
+package otlp
+
+import (
+	"encoding/binary"
+
+	v1 "github.com/jaegertracing/jaeger-idl/model/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+)
+
+// convertAttribute converts an OTLP KeyValue to a v1.KeyValue, using the
+// same VType switch shape jaeger.convertTag uses for thrift tags.
+func convertAttribute(a *commonpb.KeyValue) v1.KeyValue {
+	kv := v1.KeyValue{Key: a.Key}
+	if a.Value == nil {
+		kv.VType = v1.ValueType_STRING
+		return kv
+	}
+	switch val := a.Value.Value.(type) {
+	case *commonpb.AnyValue_BoolValue:
+		kv.VType = v1.ValueType_BOOL
+		kv.VBool = val.BoolValue
+	case *commonpb.AnyValue_IntValue:
+		kv.VType = v1.ValueType_INT64
+		kv.VInt64 = val.IntValue
+	case *commonpb.AnyValue_DoubleValue:
+		kv.VType = v1.ValueType_FLOAT64
+		kv.VFloat64 = val.DoubleValue
+	case *commonpb.AnyValue_BytesValue:
+		kv.VType = v1.ValueType_BINARY
+		kv.VBinary = val.BytesValue
+	case *commonpb.AnyValue_StringValue:
+		kv.VType = v1.ValueType_STRING
+		kv.VStr = val.StringValue
+	default: // ArrayValue, KvlistValue, or an empty AnyValue: stringify rather than assert
+		kv.VType = v1.ValueType_STRING
+		kv.VStr = a.Value.String()
+	}
+	return kv
+}
+
+// convertAttributes converts a slice of OTLP KeyValues to []v1.KeyValue.
+func convertAttributes(attrs []*commonpb.KeyValue) []v1.KeyValue {
+	if len(attrs) == 0 {
+		return nil
+	}
+	out := make([]v1.KeyValue, len(attrs))
+	for i, a := range attrs {
+		out[i] = convertAttribute(a)
+	}
+	return out
+}
+
+// convertResource converts an OTLP Resource into a v1.Process, pulling
+// service.name out as the process's ServiceName the way Jaeger's own OTLP
+// receiver does.
+func convertResource(r *resourcepb.Resource) *v1.Process {
+	proc := &v1.Process{}
+	if r == nil {
+		return proc
+	}
+	var tags []v1.KeyValue
+	for _, a := range r.Attributes {
+		if a.Key == "service.name" {
+			proc.ServiceName = a.Value.GetStringValue()
+			continue
+		}
+		tags = append(tags, convertAttribute(a))
+	}
+	proc.Tags = tags
+	return proc
+}
+
+// convertTraceID splits a 16-byte OTLP trace ID into the High/Low halves
+// the Jaeger v1 model uses.
+func convertTraceID(b []byte) v1.TraceID {
+	var id v1.TraceID
+	if len(b) == 16 {
+		id.High = binary.BigEndian.Uint64(b[:8])
+		id.Low = binary.BigEndian.Uint64(b[8:])
+	} else if len(b) == 8 {
+		id.Low = binary.BigEndian.Uint64(b)
+	}
+	return id
+}
+
+// convertSpanID converts an 8-byte OTLP span ID into a v1.SpanID.
+func convertSpanID(b []byte) v1.SpanID {
+	if len(b) != 8 {
+		return 0
+	}
+	return v1.SpanID(binary.BigEndian.Uint64(b))
+}
+
+// spanKindTag returns the "span.kind" tag OTLP consumers expect to see
+// mirrored from tracepb.Span_SpanKind.
+func spanKindTag(kind tracepb.Span_SpanKind) (v1.KeyValue, bool) {
+	var s string
+	switch kind {
+	case tracepb.Span_SPAN_KIND_CLIENT:
+		s = "client"
+	case tracepb.Span_SPAN_KIND_SERVER:
+		s = "server"
+	case tracepb.Span_SPAN_KIND_PRODUCER:
+		s = "producer"
+	case tracepb.Span_SPAN_KIND_CONSUMER:
+		s = "consumer"
+	case tracepb.Span_SPAN_KIND_INTERNAL:
+		s = "internal"
+	default:
+		return v1.KeyValue{}, false
+	}
+	return v1.KeyValue{Key: "span.kind", VType: v1.ValueType_STRING, VStr: s}, true
+}
+
+// statusTags returns the tags Jaeger uses to represent an OTLP span Status
+// (error and otel.status_code/otel.status_description), mirroring the
+// convention the Jaeger OTLP receiver uses.
+func statusTags(status *tracepb.Status) []v1.KeyValue {
+	if status == nil {
+		return nil
+	}
+	var tags []v1.KeyValue
+	if status.Code == tracepb.Status_STATUS_CODE_ERROR {
+		tags = append(tags, v1.KeyValue{Key: "error", VType: v1.ValueType_BOOL, VBool: true})
+	}
+	tags = append(tags, v1.KeyValue{
+		Key:   "otel.status_code",
+		VType: v1.ValueType_STRING,
+		VStr:  status.Code.String(),
+	})
+	if status.Message != "" {
+		tags = append(tags, v1.KeyValue{
+			Key:   "otel.status_description",
+			VType: v1.ValueType_STRING,
+			VStr:  status.Message,
+		})
+	}
+	return tags
+}
+
+// convertEvents converts OTLP span events into v1.Log entries, converting
+// each event's attributes and adding an "event" field for the event name.
+func convertEvents(events []*tracepb.Span_Event) []v1.Log {
+	if len(events) == 0 {
+		return nil
+	}
+	out := make([]v1.Log, len(events))
+	for i, e := range events {
+		fields := make([]v1.KeyValue, 0, len(e.Attributes)+1)
+		fields = append(fields, v1.KeyValue{Key: "event", VType: v1.ValueType_STRING, VStr: e.Name})
+		fields = append(fields, convertAttributes(e.Attributes)...)
+		out[i] = v1.Log{
+			Timestamp: unixNano(e.TimeUnixNano),
+			Fields:    fields,
+		}
+	}
+	return out
+}
+
+// convertSpan converts a single OTLP span, including its own trace ID,
+// into a v1.Span. The returned span has no Process set; callers attach the
+// process converted from the enclosing Resource.
+func convertSpan(s *tracepb.Span) *v1.Span {
+	traceID := convertTraceID(s.TraceId)
+
+	tags := convertAttributes(s.Attributes)
+	if kv, ok := spanKindTag(s.Kind); ok {
+		tags = append(tags, kv)
+	}
+	tags = append(tags, statusTags(s.Status)...)
+
+	var refs []v1.SpanRef
+	if len(s.ParentSpanId) == 8 {
+		refs = append(refs, v1.SpanRef{
+			TraceID: traceID,
+			SpanID:  convertSpanID(s.ParentSpanId),
+			RefType: v1.SpanRefType_CHILD_OF,
+		})
+	}
+
+	return &v1.Span{
+		TraceID:       traceID,
+		SpanID:        convertSpanID(s.SpanId),
+		OperationName: s.Name,
+		References:    refs,
+		StartTime:     unixNano(s.StartTimeUnixNano),
+		Duration:      unixNano(s.EndTimeUnixNano).Sub(unixNano(s.StartTimeUnixNano)),
+		Tags:          tags,
+		Logs:          convertEvents(s.Events),
+	}
+}
@@ -0,0 +1,119 @@
+package otlp
+
+import (
+	"testing"
+
+	v1 "github.com/jaegertracing/jaeger-idl/model/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertAttribute(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    *commonpb.KeyValue
+		expected v1.KeyValue
+	}{
+		{
+			name:     "string",
+			input:    &commonpb.KeyValue{Key: "k", Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: "v"}}},
+			expected: v1.KeyValue{Key: "k", VType: v1.ValueType_STRING, VStr: "v"},
+		},
+		{
+			name:     "bool",
+			input:    &commonpb.KeyValue{Key: "k", Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_BoolValue{BoolValue: true}}},
+			expected: v1.KeyValue{Key: "k", VType: v1.ValueType_BOOL, VBool: true},
+		},
+		{
+			name:     "int",
+			input:    &commonpb.KeyValue{Key: "k", Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: 42}}},
+			expected: v1.KeyValue{Key: "k", VType: v1.ValueType_INT64, VInt64: 42},
+		},
+		{
+			name:     "double",
+			input:    &commonpb.KeyValue{Key: "k", Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_DoubleValue{DoubleValue: 3.14}}},
+			expected: v1.KeyValue{Key: "k", VType: v1.ValueType_FLOAT64, VFloat64: 3.14},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, convertAttribute(tc.input))
+		})
+	}
+}
+
+func TestConvertAttribute_ArrayValueDoesNotPanic(t *testing.T) {
+	input := &commonpb.KeyValue{
+		Key: "k",
+		Value: &commonpb.AnyValue{
+			Value: &commonpb.AnyValue_ArrayValue{
+				ArrayValue: &commonpb.ArrayValue{
+					Values: []*commonpb.AnyValue{
+						{Value: &commonpb.AnyValue_StringValue{StringValue: "a"}},
+					},
+				},
+			},
+		},
+	}
+	assert.NotPanics(t, func() {
+		got := convertAttribute(input)
+		assert.Equal(t, v1.ValueType_STRING, got.VType)
+	})
+}
+
+func TestConvertAttribute_EmptyValueDoesNotPanic(t *testing.T) {
+	input := &commonpb.KeyValue{Key: "k", Value: &commonpb.AnyValue{}}
+	assert.NotPanics(t, func() {
+		got := convertAttribute(input)
+		assert.Equal(t, v1.ValueType_STRING, got.VType)
+	})
+}
+
+func TestConvertTraceID(t *testing.T) {
+	id := convertTraceID([]byte{0x11, 0x22, 0x33, 0x44, 0x55, 0x66, 0x77, 0x88, 0x12, 0x34, 0x56, 0x78, 0x90, 0xAB, 0xCD, 0xEF})
+	assert.Equal(t, v1.TraceID{High: 0x1122334455667788, Low: 0x1234567890ABCDEF}, id)
+}
+
+func TestConvertResource_ExtractsServiceName(t *testing.T) {
+	r := &resourcepb.Resource{
+		Attributes: []*commonpb.KeyValue{
+			{Key: "service.name", Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: "svc"}}},
+			{Key: "region", Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: "us"}}},
+		},
+	}
+	proc := convertResource(r)
+	require.NotNil(t, proc)
+	assert.Equal(t, "svc", proc.ServiceName)
+	require.Len(t, proc.Tags, 1)
+	assert.Equal(t, "region", proc.Tags[0].Key)
+}
+
+func TestConvertSpan_ParentSpanIDCreatesChildOfReference(t *testing.T) {
+	s := &tracepb.Span{
+		TraceId:      []byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1},
+		SpanId:       []byte{0, 0, 0, 0, 0, 0, 0, 2},
+		ParentSpanId: []byte{0, 0, 0, 0, 0, 0, 0, 1},
+	}
+	got := convertSpan(s)
+	require.Len(t, got.References, 1)
+	assert.Equal(t, v1.SpanRefType_CHILD_OF, got.References[0].RefType)
+	assert.Equal(t, v1.SpanID(1), got.References[0].SpanID)
+}
+
+func TestStatusTags_Error(t *testing.T) {
+	tags := statusTags(&tracepb.Status{Code: tracepb.Status_STATUS_CODE_ERROR, Message: "boom"})
+	var hasError, hasDesc bool
+	for _, kv := range tags {
+		if kv.Key == "error" && kv.VBool {
+			hasError = true
+		}
+		if kv.Key == "otel.status_description" && kv.VStr == "boom" {
+			hasDesc = true
+		}
+	}
+	assert.True(t, hasError)
+	assert.True(t, hasDesc)
+}
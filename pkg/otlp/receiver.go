@@ -0,0 +1,120 @@
+package otlp
+
+import (
+	"context"
+	"io"
+	"log"
+	"net/http"
+
+	v1 "github.com/jaegertracing/jaeger-idl/model/v1"
+	api_v2 "github.com/jaegertracing/jaeger-idl/proto-gen/api_v2"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// convertResourceSpans walks a set of OTLP ResourceSpans (ResourceSpans ->
+// ScopeSpans -> Span) and returns one v1.Batch per resource, so each
+// PostSpansRequest.Batch.Process stays coherent with the spans it covers.
+func convertResourceSpans(rss []*tracepb.ResourceSpans) []v1.Batch {
+	batches := make([]v1.Batch, 0, len(rss))
+	for _, rs := range rss {
+		proc := convertResource(rs.Resource)
+
+		var spans []*v1.Span
+		for _, ss := range rs.ScopeSpans {
+			for _, s := range ss.Spans {
+				span := convertSpan(s)
+				span.Process = proc
+				spans = append(spans, span)
+			}
+		}
+
+		batches = append(batches, v1.Batch{
+			Spans:   spans,
+			Process: proc,
+		})
+	}
+	return batches
+}
+
+// postBatches forwards each converted batch to client.PostSpans, returning
+// the first error encountered.
+func postBatches(ctx context.Context, client api_v2.CollectorServiceClient, batches []v1.Batch) error {
+	for _, batch := range batches {
+		if _, err := client.PostSpans(ctx, &api_v2.PostSpansRequest{Batch: batch}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// HandleTracesOTLPHTTP returns an http.HandlerFunc that accepts an
+// ExportTraceServiceRequest protobuf on POST /v1/traces, converts it to the
+// Jaeger v1 model and forwards it to client.PostSpans.
+func HandleTracesOTLPHTTP(client api_v2.CollectorServiceClient) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			log.Println("otlp: read body error:", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if err := r.Body.Close(); err != nil {
+			log.Println("otlp: body close error:", err)
+		}
+
+		req := &coltracepb.ExportTraceServiceRequest{}
+		if err := proto.Unmarshal(data, req); err != nil {
+			log.Println("otlp: protobuf decode failed:", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		batches := convertResourceSpans(req.ResourceSpans)
+		if err := postBatches(r.Context(), client, batches); err != nil {
+			log.Println("otlp: gRPC PostSpans error:", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		resp, err := proto.Marshal(&coltracepb.ExportTraceServiceResponse{})
+		if err != nil {
+			log.Println("otlp: response encode error:", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/x-protobuf")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(resp)
+	}
+}
+
+// TraceServiceServer implements coltracepb.TraceServiceServer, accepting
+// OTLP/gRPC export requests and forwarding them to the same
+// api_v2.CollectorServiceClient the HTTP receiver uses.
+type TraceServiceServer struct {
+	coltracepb.UnimplementedTraceServiceServer
+
+	Client api_v2.CollectorServiceClient
+}
+
+// NewTraceServiceServer returns a TraceServiceServer that forwards every
+// export request to client.
+func NewTraceServiceServer(client api_v2.CollectorServiceClient) *TraceServiceServer {
+	return &TraceServiceServer{Client: client}
+}
+
+// Export implements coltracepb.TraceServiceServer.
+func (s *TraceServiceServer) Export(ctx context.Context, req *coltracepb.ExportTraceServiceRequest) (*coltracepb.ExportTraceServiceResponse, error) {
+	batches := convertResourceSpans(req.ResourceSpans)
+	if err := postBatches(ctx, s.Client, batches); err != nil {
+		return nil, err
+	}
+	return &coltracepb.ExportTraceServiceResponse{}, nil
+}
@@ -0,0 +1,8 @@
+package otlp
+
+import "time"
+
+// unixNano converts an OTLP fixed64 unix-nano timestamp into a time.Time.
+func unixNano(ts uint64) time.Time {
+	return time.Unix(0, int64(ts))
+}
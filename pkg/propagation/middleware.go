@@ -0,0 +1,36 @@
+package propagation
+
+import (
+	"context"
+	"net/http"
+)
+
+type contextKey int
+
+const spanContextKey contextKey = 0
+
+// ContextWithSpanContext returns a copy of ctx carrying sc, retrievable
+// with SpanContextFromContext.
+func ContextWithSpanContext(ctx context.Context, sc SpanContext) context.Context {
+	return context.WithValue(ctx, spanContextKey, sc)
+}
+
+// SpanContextFromContext returns the SpanContext previously attached with
+// ContextWithSpanContext, if any.
+func SpanContextFromContext(ctx context.Context) (SpanContext, bool) {
+	sc, ok := ctx.Value(spanContextKey).(SpanContext)
+	return sc, ok
+}
+
+// WithIncomingContext returns middleware that extracts a SpanContext from
+// the incoming request's traceparent/uber-trace-id headers (if present)
+// and attaches it to the request context for next to read via
+// SpanContextFromContext.
+func WithIncomingContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if sc, ok := Extract(r.Header); ok {
+			r = r.WithContext(ContextWithSpanContext(r.Context(), sc))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,38 @@
+package propagation
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithIncomingContext_AttachesSpanContext(t *testing.T) {
+	var got SpanContext
+	var ok bool
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, ok = SpanContextFromContext(r.Context())
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/traces", nil)
+	req.Header.Set("traceparent", "00-11223344556677881234567890abcdef-0102030405060708-01")
+
+	WithIncomingContext(next).ServeHTTP(httptest.NewRecorder(), req)
+
+	require.True(t, ok)
+	assert.True(t, got.Sampled())
+}
+
+func TestWithIncomingContext_NoHeaderLeavesContextUnset(t *testing.T) {
+	var ok bool
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, ok = SpanContextFromContext(r.Context())
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/traces", nil)
+	WithIncomingContext(next).ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.False(t, ok)
+}
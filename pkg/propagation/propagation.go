@@ -0,0 +1,194 @@
+// Package propagation implements W3C trace-context (traceparent/tracestate)
+// and Jaeger baggage (uber-trace-id/uberctx-*) header propagation, so the
+// pgweb ingest surface can both read a caller's trace context and be
+// traced itself.
+package propagation
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	v1 "github.com/jaegertracing/jaeger-idl/model/v1"
+)
+
+const (
+	traceparentHeader = "Traceparent"
+	tracestateHeader  = "Tracestate"
+	uberTraceIDHeader = "Uber-Trace-Id"
+	baggagePrefix     = "Uberctx-"
+
+	traceparentVersion = "00"
+)
+
+// SpanContext is the propagated identity of a span: its trace/span IDs,
+// sampling flags, and any Jaeger baggage items.
+type SpanContext struct {
+	TraceID    v1.TraceID
+	SpanID     v1.SpanID
+	Flags      v1.Flags
+	TraceState string
+	Baggage    map[string]string
+}
+
+// Sampled reports whether the W3C sampled bit (flags & 0x1) is set.
+func (sc SpanContext) Sampled() bool {
+	return sc.Flags&1 == 1
+}
+
+// Inject writes sc onto h as a traceparent (and tracestate, if set) header,
+// a matching uber-trace-id header, and one Uberctx-<key> header per
+// baggage item.
+func Inject(sc SpanContext, h http.Header) {
+	h.Set(traceparentHeader, fmt.Sprintf(
+		"%s-%016x%016x-%016x-%02x",
+		traceparentVersion, sc.TraceID.High, sc.TraceID.Low, uint64(sc.SpanID), uint8(sc.Flags),
+	))
+	if sc.TraceState != "" {
+		h.Set(tracestateHeader, sc.TraceState)
+	}
+
+	uberFlags := 0
+	if sc.Sampled() {
+		uberFlags = 1
+	}
+	h.Set(uberTraceIDHeader, fmt.Sprintf(
+		"%016x%016x:%x:0:%d", sc.TraceID.High, sc.TraceID.Low, uint64(sc.SpanID), uberFlags,
+	))
+
+	for k, v := range sc.Baggage {
+		h.Set(baggagePrefix+k, v)
+	}
+}
+
+// Extract reads a SpanContext from h, preferring a W3C traceparent header
+// and falling back to the legacy uber-trace-id header. It returns false
+// when neither header is present or parseable.
+func Extract(h http.Header) (SpanContext, bool) {
+	if tp := h.Get(traceparentHeader); tp != "" {
+		sc, ok := parseTraceparent(tp)
+		if !ok {
+			return SpanContext{}, false
+		}
+		sc.TraceState = h.Get(tracestateHeader)
+		sc.Baggage = extractBaggage(h)
+		return sc, true
+	}
+
+	if ut := h.Get(uberTraceIDHeader); ut != "" {
+		sc, ok := parseUberTraceID(ut)
+		if !ok {
+			return SpanContext{}, false
+		}
+		sc.Baggage = extractBaggage(h)
+		return sc, true
+	}
+
+	return SpanContext{}, false
+}
+
+// parseTraceparent parses a "00-<32 hex trace id>-<16 hex span id>-<2 hex
+// flags>" traceparent value.
+func parseTraceparent(s string) (SpanContext, bool) {
+	parts := strings.Split(s, "-")
+	if len(parts) != 4 {
+		return SpanContext{}, false
+	}
+	traceIDHex, spanIDHex, flagsHex := parts[1], parts[2], parts[3]
+	if len(traceIDHex) != 32 || len(spanIDHex) != 16 || len(flagsHex) != 2 {
+		return SpanContext{}, false
+	}
+
+	high, err := strconv.ParseUint(traceIDHex[:16], 16, 64)
+	if err != nil {
+		return SpanContext{}, false
+	}
+	low, err := strconv.ParseUint(traceIDHex[16:], 16, 64)
+	if err != nil {
+		return SpanContext{}, false
+	}
+	spanID, err := strconv.ParseUint(spanIDHex, 16, 64)
+	if err != nil {
+		return SpanContext{}, false
+	}
+	flags, err := strconv.ParseUint(flagsHex, 16, 8)
+	if err != nil {
+		return SpanContext{}, false
+	}
+
+	return SpanContext{
+		TraceID: v1.TraceID{High: high, Low: low},
+		SpanID:  v1.SpanID(spanID),
+		Flags:   v1.Flags(flags),
+	}, true
+}
+
+// parseUberTraceID parses a "<trace-id>:<span-id>:<parent-id>:<flags>"
+// uber-trace-id value. trace-id may be 16 or 32 hex chars (an 8-byte or
+// 16-byte trace ID); span-id is hex and flags is decimal, matching the
+// format Jaeger clients send.
+func parseUberTraceID(s string) (SpanContext, bool) {
+	parts := strings.Split(s, ":")
+	if len(parts) != 4 {
+		return SpanContext{}, false
+	}
+	traceIDHex, spanIDHex, flagsStr := parts[0], parts[1], parts[3]
+
+	var traceID v1.TraceID
+	switch {
+	case len(traceIDHex) == 0 || len(traceIDHex) > 32:
+		return SpanContext{}, false
+	case len(traceIDHex) <= 16:
+		low, err := strconv.ParseUint(traceIDHex, 16, 64)
+		if err != nil {
+			return SpanContext{}, false
+		}
+		traceID.Low = low
+	default:
+		padded := strings.Repeat("0", 32-len(traceIDHex)) + traceIDHex
+		high, err := strconv.ParseUint(padded[:16], 16, 64)
+		if err != nil {
+			return SpanContext{}, false
+		}
+		low, err := strconv.ParseUint(padded[16:], 16, 64)
+		if err != nil {
+			return SpanContext{}, false
+		}
+		traceID.High, traceID.Low = high, low
+	}
+
+	spanID, err := strconv.ParseUint(spanIDHex, 16, 64)
+	if err != nil {
+		return SpanContext{}, false
+	}
+	flags, err := strconv.ParseUint(flagsStr, 10, 8)
+	if err != nil {
+		return SpanContext{}, false
+	}
+
+	return SpanContext{
+		TraceID: traceID,
+		SpanID:  v1.SpanID(spanID),
+		Flags:   v1.Flags(flags),
+	}, true
+}
+
+// extractBaggage reads every Uberctx-<key> header into a baggage map,
+// keyed by the lowercased <key>.
+func extractBaggage(h http.Header) map[string]string {
+	var baggage map[string]string
+	for key, values := range h {
+		if len(values) == 0 {
+			continue
+		}
+		if !strings.HasPrefix(key, baggagePrefix) {
+			continue
+		}
+		if baggage == nil {
+			baggage = make(map[string]string)
+		}
+		baggage[strings.ToLower(strings.TrimPrefix(key, baggagePrefix))] = values[0]
+	}
+	return baggage
+}
@@ -0,0 +1,111 @@
+package propagation
+
+import (
+	"net/http"
+	"testing"
+
+	v1 "github.com/jaegertracing/jaeger-idl/model/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTraceparent_RoundTrip_16ByteTraceID(t *testing.T) {
+	sc := SpanContext{
+		TraceID: v1.TraceID{High: 0x1122334455667788, Low: 0x1234567890abcdef},
+		SpanID:  v1.SpanID(0x0102030405060708),
+		Flags:   v1.Flags(1),
+	}
+
+	h := http.Header{}
+	Inject(sc, h)
+
+	got, ok := Extract(h)
+	require.True(t, ok)
+	assert.Equal(t, sc.TraceID, got.TraceID)
+	assert.Equal(t, sc.SpanID, got.SpanID)
+	assert.Equal(t, v1.Flags(1), got.Flags)
+	assert.True(t, got.Sampled())
+}
+
+func TestTraceparent_RoundTrip_8ByteTraceID(t *testing.T) {
+	sc := SpanContext{
+		TraceID: v1.TraceID{High: 0, Low: 0x1234567890abcdef},
+		SpanID:  v1.SpanID(0x0102030405060708),
+		Flags:   v1.Flags(0),
+	}
+
+	h := http.Header{}
+	Inject(sc, h)
+
+	got, ok := Extract(h)
+	require.True(t, ok)
+	assert.Equal(t, sc.TraceID, got.TraceID)
+	assert.Equal(t, sc.SpanID, got.SpanID)
+	assert.False(t, got.Sampled())
+}
+
+func TestParseTraceparent_SampledFlag(t *testing.T) {
+	h := http.Header{}
+	h.Set("traceparent", "00-11223344556677881234567890abcdef-0102030405060708-01")
+
+	got, ok := Extract(h)
+	require.True(t, ok)
+	assert.Equal(t, v1.Flags(1), got.Flags)
+	assert.True(t, got.Sampled())
+}
+
+func TestExtract_UberTraceID_8ByteTraceID(t *testing.T) {
+	sc := SpanContext{
+		TraceID: v1.TraceID{High: 0, Low: 0x1234567890abcdef},
+		SpanID:  v1.SpanID(0x0102030405060708),
+		Flags:   v1.Flags(1),
+	}
+
+	h := http.Header{}
+	h.Set("uber-trace-id", "1234567890abcdef:0102030405060708:0:1")
+
+	got, ok := Extract(h)
+	require.True(t, ok)
+	assert.Equal(t, sc.TraceID, got.TraceID)
+	assert.Equal(t, sc.SpanID, got.SpanID)
+	assert.True(t, got.Sampled())
+}
+
+func TestExtract_UberTraceID_16ByteTraceID(t *testing.T) {
+	sc := SpanContext{
+		TraceID: v1.TraceID{High: 0x1122334455667788, Low: 0x1234567890abcdef},
+		SpanID:  v1.SpanID(0x0102030405060708),
+		Flags:   v1.Flags(0),
+	}
+
+	h := http.Header{}
+	h.Set("uber-trace-id", "11223344556677881234567890abcdef:0102030405060708:0:0")
+
+	got, ok := Extract(h)
+	require.True(t, ok)
+	assert.Equal(t, sc.TraceID, got.TraceID)
+	assert.Equal(t, sc.SpanID, got.SpanID)
+	assert.False(t, got.Sampled())
+}
+
+func TestExtractBaggage(t *testing.T) {
+	h := http.Header{}
+	h.Set("traceparent", "00-11223344556677881234567890abcdef-0102030405060708-01")
+	h.Set("uberctx-userid", "42")
+
+	got, ok := Extract(h)
+	require.True(t, ok)
+	require.NotNil(t, got.Baggage)
+	assert.Equal(t, "42", got.Baggage["userid"])
+}
+
+func TestInject_SetsUberTraceIDHeader(t *testing.T) {
+	sc := SpanContext{
+		TraceID: v1.TraceID{Low: 0x1},
+		SpanID:  v1.SpanID(0x2),
+		Flags:   v1.Flags(1),
+	}
+	h := http.Header{}
+	Inject(sc, h)
+	assert.Equal(t, "00000000000000000000000000000001:2:0:1", h.Get("uber-trace-id"))
+}
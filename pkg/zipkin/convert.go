@@ -0,0 +1,187 @@
+// Package zipkin receives Zipkin v2 JSON spans and converts them into the
+// Jaeger v1 model so they can be forwarded through the same
+// api_v2.CollectorServiceClient the jaeger package uses.
+package zipkin
+
+import (
+	"encoding/hex"
+	"strings"
+	"time"
+
+	v1 "github.com/jaegertracing/jaeger-idl/model/v1"
+)
+
+// span is the Zipkin v2 JSON span shape, as POSTed to /api/v2/spans.
+type span struct {
+	TraceID       string            `json:"traceId"`
+	ID            string            `json:"id"`
+	ParentID      string            `json:"parentId"`
+	Name          string            `json:"name"`
+	Kind          string            `json:"kind"`
+	Timestamp     int64             `json:"timestamp"`
+	Duration      int64             `json:"duration"`
+	LocalEndpoint *endpoint         `json:"localEndpoint"`
+	Tags          map[string]string `json:"tags"`
+	Annotations   []annotation      `json:"annotations"`
+}
+
+type endpoint struct {
+	ServiceName string `json:"serviceName"`
+}
+
+type annotation struct {
+	Timestamp int64  `json:"timestamp"`
+	Value     string `json:"value"`
+}
+
+// convertTraceID hex-decodes a Zipkin trace ID: a 32-char ID maps its top
+// 16 hex chars to TraceID.High and bottom 16 to TraceID.Low, while a
+// 16-char ID maps entirely to TraceID.Low.
+func convertTraceID(s string) v1.TraceID {
+	var id v1.TraceID
+	switch len(s) {
+	case 32:
+		if b, err := hex.DecodeString(s[:16]); err == nil {
+			id.High = bytesToUint64(b)
+		}
+		if b, err := hex.DecodeString(s[16:]); err == nil {
+			id.Low = bytesToUint64(b)
+		}
+	case 16:
+		if b, err := hex.DecodeString(s); err == nil {
+			id.Low = bytesToUint64(b)
+		}
+	}
+	return id
+}
+
+// convertSpanID hex-decodes a Zipkin 16-char span/parent ID into a v1.SpanID.
+func convertSpanID(s string) v1.SpanID {
+	if len(s) != 16 {
+		return 0
+	}
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return 0
+	}
+	return v1.SpanID(bytesToUint64(b))
+}
+
+func bytesToUint64(b []byte) uint64 {
+	var v uint64
+	for _, c := range b {
+		v = v<<8 | uint64(c)
+	}
+	return v
+}
+
+// convertKindTag maps a Zipkin span kind ("CLIENT", "SERVER", "PRODUCER",
+// "CONSUMER") to the "span.kind" tag Jaeger consumers expect.
+func convertKindTag(kind string) (v1.KeyValue, bool) {
+	if kind == "" {
+		return v1.KeyValue{}, false
+	}
+	return v1.KeyValue{
+		Key:   "span.kind",
+		VType: v1.ValueType_STRING,
+		VStr:  strings.ToLower(kind),
+	}, true
+}
+
+// convertTags converts Zipkin's flat string tag map into []v1.KeyValue,
+// using the same string-typed convention jaeger.convertTag falls back to.
+func convertTags(tags map[string]string) []v1.KeyValue {
+	if len(tags) == 0 {
+		return nil
+	}
+	out := make([]v1.KeyValue, 0, len(tags))
+	for k, v := range tags {
+		out = append(out, v1.KeyValue{Key: k, VType: v1.ValueType_STRING, VStr: v})
+	}
+	return out
+}
+
+// convertAnnotations converts Zipkin annotations into v1.Log entries.
+func convertAnnotations(anns []annotation) []v1.Log {
+	if len(anns) == 0 {
+		return nil
+	}
+	out := make([]v1.Log, len(anns))
+	for i, a := range anns {
+		out[i] = v1.Log{
+			Timestamp: time.UnixMicro(a.Timestamp),
+			Fields: []v1.KeyValue{
+				{Key: "event", VType: v1.ValueType_STRING, VStr: a.Value},
+			},
+		}
+	}
+	return out
+}
+
+// convertSpan converts a single Zipkin span into a v1.Span. The returned
+// span has no Process set; callers group spans by service and attach the
+// shared v1.Process separately.
+func convertSpan(s span) *v1.Span {
+	traceID := convertTraceID(s.TraceID)
+
+	tags := convertTags(s.Tags)
+	if kv, ok := convertKindTag(s.Kind); ok {
+		tags = append(tags, kv)
+	}
+
+	var refs []v1.SpanRef
+	if s.ParentID != "" {
+		refs = append(refs, v1.SpanRef{
+			TraceID: traceID,
+			SpanID:  convertSpanID(s.ParentID),
+			RefType: v1.SpanRefType_CHILD_OF,
+		})
+	}
+
+	return &v1.Span{
+		TraceID:       traceID,
+		SpanID:        convertSpanID(s.ID),
+		OperationName: s.Name,
+		References:    refs,
+		StartTime:     time.UnixMicro(s.Timestamp),
+		Duration:      time.Duration(s.Duration) * time.Microsecond,
+		Tags:          tags,
+		Logs:          convertAnnotations(s.Annotations),
+	}
+}
+
+// serviceName returns the service name to group a span's process under,
+// falling back to the empty string when no localEndpoint is set.
+func serviceName(s span) string {
+	if s.LocalEndpoint == nil {
+		return ""
+	}
+	return s.LocalEndpoint.ServiceName
+}
+
+// groupByService converts a slice of Zipkin spans into one v1.Batch per
+// distinct service name, so each PostSpansRequest.Batch.Process stays
+// coherent with the spans it covers.
+func groupByService(spans []span) []v1.Batch {
+	order := make([]string, 0)
+	bySvc := make(map[string][]*v1.Span)
+
+	for _, s := range spans {
+		svc := serviceName(s)
+		converted := convertSpan(s)
+		if _, ok := bySvc[svc]; !ok {
+			order = append(order, svc)
+		}
+		bySvc[svc] = append(bySvc[svc], converted)
+	}
+
+	batches := make([]v1.Batch, 0, len(order))
+	for _, svc := range order {
+		proc := &v1.Process{ServiceName: svc}
+		for _, sp := range bySvc[svc] {
+			sp.Process = proc
+		}
+		batches = append(batches, v1.Batch{Spans: bySvc[svc], Process: proc})
+	}
+	return batches
+}
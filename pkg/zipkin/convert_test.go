@@ -0,0 +1,62 @@
+package zipkin
+
+import (
+	"testing"
+
+	v1 "github.com/jaegertracing/jaeger-idl/model/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertTraceID_32Char(t *testing.T) {
+	id := convertTraceID("1122334455667788" + "1234567890abcdef")
+	assert.Equal(t, v1.TraceID{High: 0x1122334455667788, Low: 0x1234567890abcdef}, id)
+}
+
+func TestConvertTraceID_16Char(t *testing.T) {
+	id := convertTraceID("1234567890abcdef")
+	assert.Equal(t, v1.TraceID{High: 0, Low: 0x1234567890abcdef}, id)
+}
+
+func TestConvertSpan_ParentIDCreatesChildOfReference(t *testing.T) {
+	s := span{
+		TraceID:  "1234567890abcdef",
+		ID:       "0000000000000002",
+		ParentID: "0000000000000001",
+	}
+	got := convertSpan(s)
+	require.Len(t, got.References, 1)
+	assert.Equal(t, v1.SpanRefType_CHILD_OF, got.References[0].RefType)
+	assert.Equal(t, v1.SpanID(1), got.References[0].SpanID)
+}
+
+func TestGroupByService_GroupsAndSetsProcess(t *testing.T) {
+	spans := []span{
+		{TraceID: "1234567890abcdef", ID: "0000000000000001", LocalEndpoint: &endpoint{ServiceName: "a"}},
+		{TraceID: "1234567890abcdef", ID: "0000000000000002", LocalEndpoint: &endpoint{ServiceName: "b"}},
+		{TraceID: "1234567890abcdef", ID: "0000000000000003", LocalEndpoint: &endpoint{ServiceName: "a"}},
+	}
+
+	batches := groupByService(spans)
+	require.Len(t, batches, 2)
+
+	var aBatch *v1.Batch
+	for i := range batches {
+		if batches[i].Process.ServiceName == "a" {
+			aBatch = &batches[i]
+		}
+	}
+	require.NotNil(t, aBatch)
+	require.Len(t, aBatch.Spans, 2)
+	assert.Same(t, aBatch.Process, aBatch.Spans[0].Process)
+}
+
+func TestConvertKindTag(t *testing.T) {
+	kv, ok := convertKindTag("CLIENT")
+	require.True(t, ok)
+	assert.Equal(t, "span.kind", kv.Key)
+	assert.Equal(t, "client", kv.VStr, "must match the lowercase convention the OTLP receiver uses")
+
+	_, ok = convertKindTag("")
+	assert.False(t, ok)
+}
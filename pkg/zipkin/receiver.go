@@ -0,0 +1,51 @@
+package zipkin
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+
+	api_v2 "github.com/jaegertracing/jaeger-idl/proto-gen/api_v2"
+)
+
+// HandleZipkinJSON returns an http.HandlerFunc that accepts a Zipkin v2
+// JSON array of spans on POST /api/v2/spans, converts them into the
+// Jaeger v1 model (grouped by service so each Batch.Process stays
+// coherent), and forwards them to client.PostSpans.
+func HandleZipkinJSON(client api_v2.CollectorServiceClient) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			log.Println("zipkin: read body error:", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if err := r.Body.Close(); err != nil {
+			log.Println("zipkin: body close error:", err)
+		}
+
+		var spans []span
+		if err := json.Unmarshal(data, &spans); err != nil {
+			log.Println("zipkin: JSON decode failed:", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		for _, batch := range groupByService(spans) {
+			if _, err := client.PostSpans(context.Background(), &api_v2.PostSpansRequest{Batch: batch}); err != nil {
+				log.Println("zipkin: gRPC PostSpans error:", err)
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+		}
+
+		w.WriteHeader(http.StatusAccepted)
+	}
+}